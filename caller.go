@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+)
+
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// CallerInfo identifies the user's call site, i.e. the first stack frame
+// outside this package.
+type CallerInfo struct {
+	File string
+	Line int
+	Func string
+}
+
+// captureCaller walks up the stack past this package's own frames and
+// returns the first frame outside it, or nil if none is found.
+func captureCaller() *CallerInfo {
+	for skip := 2; skip < 25; skip++ {
+		pc, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			break
+		}
+		if filepath.Dir(file) != packageDir {
+			name := ""
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				name = fn.Name()
+			}
+			return &CallerInfo{File: file, Line: line, Func: name}
+		}
+	}
+	return nil
+}
+
+// findCaller is a convenience wrapper around captureCaller for callers
+// that only need file:line, such as CountSampler's call-site key.
+func findCaller() (file string, line int) {
+	if ci := captureCaller(); ci != nil {
+		return ci.File, ci.Line
+	}
+	return "", 0
+}
@@ -0,0 +1,30 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+
+	logger "github.com/NobeKanai/logger"
+	"github.com/NobeKanai/logger/internal/callerfixture"
+)
+
+// TestReportCallerPointsAtUserCode exercises captureCaller end to end from
+// a call site in another package, where the directory-based skip actually
+// applies.
+func TestReportCallerPointsAtUserCode(t *testing.T) {
+	var buf strings.Builder
+	logger.SetOutput(&buf)
+	defer logger.SetOutput(nil)
+	logger.SetReportCaller(true)
+	defer logger.SetReportCaller(false)
+
+	callerfixture.CallError("boom")
+
+	out := buf.String()
+	if !strings.Contains(out, "fixture.go:") {
+		t.Fatalf("expected caller to point at fixture.go, got: %q", out)
+	}
+	if strings.Contains(out, "logger.go:") {
+		t.Fatalf("caller should skip this package's own frames, got: %q", out)
+	}
+}
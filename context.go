@@ -0,0 +1,21 @@
+package logger
+
+import "context"
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying entry, retrievable later with
+// FromContext.
+func NewContext(ctx context.Context, entry *Entry) context.Context {
+	return context.WithValue(ctx, ctxKey{}, entry)
+}
+
+// FromContext returns the Entry previously attached to ctx via NewContext.
+// If none is present it returns a fresh empty Entry, so callers can always
+// chain WithField/Info/etc without a nil check.
+func FromContext(ctx context.Context) *Entry {
+	if entry, ok := ctx.Value(ctxKey{}).(*Entry); ok {
+		return entry
+	}
+	return newEntry()
+}
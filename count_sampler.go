@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CountSampler emits the first N occurrences of each distinct (level,
+// format string, caller file:line) and every Mth occurrence after that,
+// so a hot loop still surfaces the occasional log line instead of going
+// completely silent.
+type CountSampler struct {
+	first int
+	every int
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewCountSampler returns a sampler emitting the first `first` occurrences
+// of each call site, then every `every`th occurrence after that. An every
+// of 0 suppresses everything past the first `first`.
+func NewCountSampler(first, every int) *CountSampler {
+	return &CountSampler{first: first, every: every, counts: make(map[string]int)}
+}
+
+func (s *CountSampler) Allow(entry *Entry, format string) bool {
+	file, line := findCaller()
+	key := fmt.Sprintf("%s|%s|%s:%d", entry.Level, format, file, line)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.counts[key]
+	s.counts[key] = n + 1
+
+	if n < s.first {
+		return true
+	}
+	return s.every > 0 && (n-s.first)%s.every == 0
+}
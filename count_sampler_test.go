@@ -0,0 +1,44 @@
+package logger
+
+import "testing"
+
+func TestCountSamplerAllow(t *testing.T) {
+	s := NewCountSampler(2, 3)
+	entry := &Entry{Level: ErrorLevel}
+
+	// first is 2: the first two occurrences always pass.
+	if !s.Allow(entry, "boom %d") {
+		t.Fatal("1st occurrence should be allowed")
+	}
+	if !s.Allow(entry, "boom %d") {
+		t.Fatal("2nd occurrence should be allowed")
+	}
+
+	// every is 3: past `first`, only every 3rd occurrence passes.
+	var got []bool
+	for i := 0; i < 6; i++ {
+		got = append(got, s.Allow(entry, "boom %d"))
+	}
+	want := []bool{true, false, false, true, false, false}
+	for i, w := range want {
+		if got[i] != w {
+			t.Fatalf("occurrence %d: got %v, want %v (full: %v)", i+3, got[i], w, got)
+		}
+	}
+}
+
+func TestCountSamplerKeysByCallSite(t *testing.T) {
+	s := NewCountSampler(1, 0)
+	entryA := &Entry{Level: ErrorLevel}
+	entryB := &Entry{Level: WarnLevel}
+
+	if !s.Allow(entryA, "same format") {
+		t.Fatal("first occurrence for ErrorLevel should be allowed")
+	}
+	if !s.Allow(entryB, "same format") {
+		t.Fatal("a different level should be a distinct key, so it should also be allowed")
+	}
+	if s.Allow(entryA, "same format") {
+		t.Fatal("second occurrence at the same (level, format, call site) should be suppressed once past `first`, with every=0")
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Hook is implemented by log sinks that want to receive entries for a
+// subset of levels, e.g. shipping Error/Fatal entries to Slack while a
+// file hook keeps everything.
+type Hook interface {
+	// Levels returns the levels this hook should fire on.
+	Levels() []LogLevel
+	// Fire is called synchronously for every Entry logged at one of Levels.
+	Fire(entry *Entry) error
+}
+
+var hooks = make(map[LogLevel][]Hook)
+
+// AddHook registers hook to fire on every level it declares via Levels.
+func AddHook(hook Hook) {
+	for _, level := range hook.Levels() {
+		hooks[level] = append(hooks[level], hook)
+	}
+}
+
+func fireHooks(entry *Entry) {
+	for _, hook := range hooks[entry.Level] {
+		if err := hook.Fire(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: hook failed to fire: %v\n", err)
+		}
+	}
+}
+
+// Flusher is implemented by hooks that buffer entries (e.g. RemoteShipper)
+// and need a chance to drain before the process exits. Fatal flushes every
+// registered hook implementing Flusher before calling os.Exit, so a fatal
+// entry isn't lost in an in-flight buffer.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+func flushHooks() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	seen := make(map[Hook]bool)
+	for _, list := range hooks {
+		for _, hook := range list {
+			if seen[hook] {
+				continue
+			}
+			seen[hook] = true
+			if f, ok := hook.(Flusher); ok {
+				if err := f.Flush(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "logger: hook failed to flush: %v\n", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package filehook appends log entries to a file on disk, rotating to a
+// fresh file once the current one grows past a configured size.
+package filehook
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	logger "github.com/NobeKanai/logger"
+)
+
+// Hook writes formatted entries to a file, rotating it once MaxSize is
+// exceeded. The zero value is not usable; construct one with New.
+type Hook struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	levels  []logger.LogLevel
+
+	file *os.File
+	size int64
+}
+
+// New opens (creating if needed) path for appending. maxSize of 0 disables
+// rotation. levels defaults to every level when omitted.
+func New(path string, maxSize int64, levels ...logger.LogLevel) (*Hook, error) {
+	if len(levels) == 0 {
+		levels = []logger.LogLevel{logger.FatalLevel, logger.ErrorLevel, logger.WarnLevel, logger.InfoLevel, logger.DebugLevel}
+	}
+
+	h := &Hook{path: path, maxSize: maxSize, levels: levels}
+	if err := h.open(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *Hook) open() error {
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	h.file = f
+	h.size = info.Size()
+	return nil
+}
+
+func (h *Hook) Levels() []logger.LogLevel { return h.levels }
+
+func (h *Hook) Fire(entry *logger.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	line := []byte(fmt.Sprintf("[%s] [%s] %s\n", entry.Time.Format("2006-01-02T15:04:05"), entry.Level, entry.Message))
+
+	if h.maxSize > 0 && h.size+int64(len(line)) > h.maxSize {
+		if err := h.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := h.file.Write(line)
+	h.size += int64(n)
+	return err
+}
+
+func (h *Hook) rotate() error {
+	if err := h.file.Close(); err != nil {
+		return err
+	}
+	rotated := h.path + "." + time.Now().Format("20060102T150405")
+	if err := os.Rename(h.path, rotated); err != nil {
+		return err
+	}
+	return h.open()
+}
+
+// Close flushes and closes the underlying file.
+func (h *Hook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.file.Close()
+}
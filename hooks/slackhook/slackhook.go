@@ -0,0 +1,47 @@
+// Package slackhook posts Error and Fatal entries to a Slack incoming
+// webhook.
+package slackhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	logger "github.com/NobeKanai/logger"
+)
+
+// Hook posts a Slack message for every Error/Fatal entry it fires on.
+type Hook struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// New returns a Hook posting to the given Slack incoming webhook URL.
+func New(webhookURL string) *Hook {
+	return &Hook{webhookURL: webhookURL, client: http.DefaultClient}
+}
+
+func (h *Hook) Levels() []logger.LogLevel {
+	return []logger.LogLevel{logger.FatalLevel, logger.ErrorLevel}
+}
+
+func (h *Hook) Fire(entry *logger.Entry) error {
+	payload, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: fmt.Sprintf("*%s* %s", entry.Level, entry.Message)})
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Post(h.webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slackhook: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+// Package syslog ships log entries to the local syslog daemon.
+package syslog
+
+import (
+	sysl "log/syslog"
+
+	logger "github.com/NobeKanai/logger"
+)
+
+// Hook forwards entries to syslog, mapping each LogLevel to the matching
+// syslog severity.
+type Hook struct {
+	writer *sysl.Writer
+	levels []logger.LogLevel
+}
+
+// New dials the local syslog daemon under tag and returns a Hook that
+// fires on every level up to and including maxLevel.
+func New(tag string, maxLevel logger.LogLevel) (*Hook, error) {
+	w, err := sysl.New(sysl.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]logger.LogLevel, 0, maxLevel+1)
+	for l := logger.FatalLevel; l <= maxLevel; l++ {
+		levels = append(levels, l)
+	}
+	return &Hook{writer: w, levels: levels}, nil
+}
+
+func (h *Hook) Levels() []logger.LogLevel { return h.levels }
+
+func (h *Hook) Fire(entry *logger.Entry) error {
+	switch entry.Level {
+	case logger.FatalLevel:
+		return h.writer.Crit(entry.Message)
+	case logger.ErrorLevel:
+		return h.writer.Err(entry.Message)
+	case logger.WarnLevel:
+		return h.writer.Warning(entry.Message)
+	case logger.InfoLevel:
+		return h.writer.Info(entry.Message)
+	default:
+		return h.writer.Debug(entry.Message)
+	}
+}
@@ -0,0 +1,12 @@
+// Package callerfixture is a minimal, separately-located caller for tests
+// that need to verify logger's caller-reporting skips its own frames.
+package callerfixture
+
+import logger "github.com/NobeKanai/logger"
+
+// CallError logs msg at Error level so a caller in the logger package's
+// own test suite can assert the reported file:line is this file, not one
+// of logger's.
+func CallError(msg string) {
+	logger.Error(msg)
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetLevel sets the minimum level that will be logged, e.g.
+// SetLevel(logger.WarnLevel) to silence Info and Debug.
+func SetLevel(level LogLevel) {
+	requestedLevel = level
+}
+
+// ParseLevel parses a level name ("debug", "info", "warn"/"warning",
+// "error", "fatal", case-insensitive) into a LogLevel.
+func ParseLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	default:
+		return 0, fmt.Errorf("logger: unknown level %q", s)
+	}
+}
+
+func init() {
+	lvl := os.Getenv("LOGGER_LEVEL")
+	if lvl == "" {
+		return
+	}
+	level, err := ParseLevel(lvl)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: ignoring LOGGER_LEVEL: %v\n", err)
+		return
+	}
+	requestedLevel = level
+}
@@ -0,0 +1,30 @@
+package logger
+
+import "testing"
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]LogLevel{
+		"debug":   DebugLevel,
+		"DEBUG":   DebugLevel,
+		"info":    InfoLevel,
+		"warn":    WarnLevel,
+		"warning": WarnLevel,
+		"error":   ErrorLevel,
+		"fatal":   FatalLevel,
+	}
+	for name, want := range cases {
+		got, err := ParseLevel(name)
+		if err != nil {
+			t.Errorf("ParseLevel(%q): unexpected error: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseLevelInvalid(t *testing.T) {
+	if _, err := ParseLevel("bogus"); err == nil {
+		t.Fatal("expected an error for an unknown level name")
+	}
+}
@@ -1,19 +1,22 @@
 package logger
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
-	"net/http"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
 
 var (
-	requestedLevel               = InfoLevel
-	remoteServer                 = ""
-	c              chan string   = nil
-	stop           chan struct{} = nil
+	requestedLevel = InfoLevel
+
+	formatter    Formatter = &TextFormatter{}
+	output       io.Writer = nil
+	reportCaller           = false
 )
 
 type LogLevel uint32
@@ -43,116 +46,244 @@ func (level LogLevel) String() string {
 	}
 }
 
-func init() {
-	remoteServer = os.Getenv("LOGGER_REMOTE_SERVER")
-	if remoteServer == "" {
-		return
-	}
+// Fields is a shorthand for the loosely typed bag of structured data
+// attached to an Entry.
+type Fields map[string]interface{}
+
+// Entry represents a single log event, possibly carrying structured
+// fields added via WithField/WithFields.
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Data    Fields
 
-	Info("Enable pushing error/fatal logs to remote server %q", remoteServer)
-
-	c = make(chan string, 20)
-	stop = make(chan struct{})
-
-	go func() {
-		tick := time.Tick(5 * time.Second)
-		messages := make(map[string]struct{})
-		send := func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-
-			msgPack := ""
-			for msg := range messages {
-				msgPack += msg + "\n"
-				delete(messages, msg)
-			}
-			if msgPack != "" {
-				req, err := http.NewRequestWithContext(ctx, http.MethodPost, remoteServer, strings.NewReader(msgPack[:len(msgPack)-1]))
-				r, err := http.DefaultClient.Do(req)
-				if err != nil {
-					Warn("Cannot push logs to remote server: %v", err)
-					return
-				}
-				r.Body.Close()
-			}
-		}
-
-		for {
-			select {
-			case <-tick:
-				send()
-			case msg := <-c:
-				messages[msg] = struct{}{}
-			case <-stop:
-				for msg := range c {
-					messages[msg] = struct{}{}
-				}
-				send()
-				os.Exit(1)
-			}
-		}
-	}()
+	// Caller is set when SetReportCaller(true) is in effect.
+	Caller *CallerInfo
+	// Stack is set for Error/Fatal entries when stack capture is enabled;
+	// see SetStackTraceDepth.
+	Stack []string
 }
 
-// EnableDebug increases logging, more verbose (debug)
-func EnableDebug() {
-	requestedLevel = DebugLevel
-	fmt.Fprintln(os.Stdout, formatMessage(InfoLevel, "Debug mode enabled"))
+func newEntry() *Entry {
+	return &Entry{Data: make(Fields)}
 }
 
-// Debug sends a debug log message.
-func Debug(format string, v ...interface{}) {
-	if requestedLevel >= DebugLevel {
-		fmt.Fprintln(os.Stdout, formatMessage(DebugLevel, format, v...))
+// WithField returns a new Entry carrying the given key/value, leaving the
+// package-level logger untouched.
+func WithField(key string, val interface{}) *Entry {
+	return newEntry().WithField(key, val)
+}
+
+// WithFields returns a new Entry carrying the given fields, leaving the
+// package-level logger untouched.
+func WithFields(fields Fields) *Entry {
+	return newEntry().WithFields(fields)
+}
+
+// WithField returns the Entry itself with key/val merged into its fields,
+// so calls can be chained: WithField("a", 1).WithField("b", 2).
+func (e *Entry) WithField(key string, val interface{}) *Entry {
+	e.Data[key] = val
+	return e
+}
+
+// WithFields merges fields into the Entry and returns it for chaining.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	for k, v := range fields {
+		e.Data[k] = v
 	}
+	return e
 }
 
-// Info sends an info log message.
-func Info(format string, v ...interface{}) {
-	if requestedLevel >= InfoLevel {
-		fmt.Fprintln(os.Stdout, formatMessage(InfoLevel, format, v...))
+func (e *Entry) log(level LogLevel, format string, v ...interface{}) {
+	if requestedLevel < level {
+		return
+	}
+
+	e.Time = time.Now()
+	e.Level = level
+	e.Message = fmt.Sprintf(format, v...)
+
+	if sampler != nil && level != FatalLevel && !sampler.Allow(e, format) {
+		return
+	}
+
+	if reportCaller {
+		e.Caller = captureCaller()
+	}
+	if (level == ErrorLevel || level == FatalLevel) && stackTraceDepth > 0 {
+		e.Stack = captureStack(stackTraceDepth)
+	}
+
+	line, err := formatter.Format(e)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logger: failed to format entry:", err)
+		return
+	}
+
+	w := output
+	if w == nil {
+		w = defaultWriter(level)
+	}
+	w.Write(line)
+
+	fireHooks(e)
+
+	if level == FatalLevel {
+		flushHooks()
+		os.Exit(1)
 	}
 }
 
-// Warn sends a warn log message.
-func Warn(format string, v ...interface{}) {
-	if requestedLevel >= WarnLevel {
-		fmt.Fprintln(os.Stderr, formatMessage(WarnLevel, format, v...))
+func defaultWriter(level LogLevel) io.Writer {
+	if level == DebugLevel || level == InfoLevel {
+		return os.Stdout
 	}
+	return os.Stderr
 }
 
+// Debug sends a debug log message.
+func (e *Entry) Debug(format string, v ...interface{}) { e.log(DebugLevel, format, v...) }
+
+// Info sends an info log message.
+func (e *Entry) Info(format string, v ...interface{}) { e.log(InfoLevel, format, v...) }
+
+// Warn sends a warn log message.
+func (e *Entry) Warn(format string, v ...interface{}) { e.log(WarnLevel, format, v...) }
+
 // Error sends an error log message.
-func Error(format string, v ...interface{}) {
-	if requestedLevel >= ErrorLevel {
-		msg := formatMessage(ErrorLevel, format, v...)
-		Push(msg)
-		fmt.Fprintln(os.Stderr, msg)
+func (e *Entry) Error(format string, v ...interface{}) { e.log(ErrorLevel, format, v...) }
+
+// Fatal sends a fatal log message and stops the execution of the program.
+func (e *Entry) Fatal(format string, v ...interface{}) { e.log(FatalLevel, format, v...) }
+
+// Formatter renders an Entry into the bytes that get written to the
+// configured output.
+type Formatter interface {
+	Format(entry *Entry) ([]byte, error)
+}
+
+// TextFormatter renders entries the way this package always has:
+// "[time] [LEVEL] message", followed by any fields as space-separated
+// key=value pairs. It is the default formatter.
+type TextFormatter struct{}
+
+func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] [%s] ", entry.Time.Format("2006-01-02T15:04:05"), entry.Level)
+	if entry.Caller != nil {
+		fmt.Fprintf(&b, "%s:%d ", filepath.Base(entry.Caller.File), entry.Caller.Line)
+	}
+	b.WriteString(entry.Message)
+
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, entry.Data[k])
 	}
+
+	for _, frame := range entry.Stack {
+		fmt.Fprintf(&b, "\n\t%s", frame)
+	}
+
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
 }
 
-// Fatal sends a fatal log message and stop the execution of the program.
-func Fatal(format string, v ...interface{}) {
-	if requestedLevel >= FatalLevel {
-		msg := formatMessage(FatalLevel, format, v...)
-		fmt.Fprintln(os.Stderr, msg)
-		if remoteServer != "" {
-			c <- msg
-			close(c)
-			stop <- struct{}{}
-		} else {
-			os.Exit(1)
-		}
+// JSONFormatter renders one JSON object per line with "time", "level" and
+// "msg" keys plus whatever fields were attached to the Entry, matching the
+// shape most log-aggregation pipelines (ELK, Loki, Datadog) expect.
+type JSONFormatter struct{}
+
+func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	data := make(Fields, len(entry.Data)+3)
+	for k, v := range entry.Data {
+		data[k] = v
 	}
+	data["time"] = entry.Time.Format(time.RFC3339)
+	data["level"] = entry.Level.String()
+	data["msg"] = entry.Message
+	if entry.Caller != nil {
+		data["caller"] = fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+	}
+	if len(entry.Stack) > 0 {
+		data["stack"] = entry.Stack
+	}
+
+	line, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// SetFormatter changes how entries are rendered, e.g. SetFormatter(&logger.JSONFormatter{}).
+func SetFormatter(f Formatter) {
+	formatter = f
+}
+
+// SetOutput redirects all log levels to w, overriding the default
+// stdout/stderr split.
+func SetOutput(w io.Writer) {
+	output = w
+}
+
+// SetReportCaller enables or disables annotating every Entry with the
+// file:line (and function) of its call site, skipping this package's own
+// frames so the reported location is always the user's.
+func SetReportCaller(enabled bool) {
+	reportCaller = enabled
 }
 
-// Push pushs message to remote server(if have)
-func Push(message string) {
-	if remoteServer != "" {
-		c <- message
+// envRemoteShipper is the RemoteShipper created from LOGGER_REMOTE_SERVER,
+// if that env var was set. Retrieve it with RemoteShipperFromEnv to Close
+// it on shutdown so buffered logs get a chance to drain.
+var envRemoteShipper *RemoteShipper
+
+// RemoteShipperFromEnv returns the RemoteShipper registered from the
+// LOGGER_REMOTE_SERVER env var, or nil if that var wasn't set. Callers
+// should Close(ctx) it during graceful shutdown.
+func RemoteShipperFromEnv() *RemoteShipper {
+	return envRemoteShipper
+}
+
+func init() {
+	url := os.Getenv("LOGGER_REMOTE_SERVER")
+	if url == "" {
+		return
 	}
+
+	spillPath := os.Getenv("LOGGER_REMOTE_SPILL_FILE")
+	if spillPath == "" {
+		spillPath = filepath.Join(os.TempDir(), "logger-remote-spill.jsonl")
+	}
+
+	envRemoteShipper = NewRemoteShipper(url, WithSpillFile(spillPath))
+	AddHook(envRemoteShipper)
+	Info("Enable pushing error/fatal logs to remote server %q", url)
 }
 
-func formatMessage(level LogLevel, format string, v ...interface{}) string {
-	prefix := fmt.Sprintf("[%s] [%s] ", time.Now().Format("2006-01-02T15:04:05"), level)
-	return fmt.Sprintf(prefix+format, v...)
+// EnableDebug increases logging, more verbose (debug)
+func EnableDebug() {
+	SetLevel(DebugLevel)
+	newEntry().Info("Debug mode enabled")
 }
+
+// Debug sends a debug log message.
+func Debug(format string, v ...interface{}) { newEntry().Debug(format, v...) }
+
+// Info sends an info log message.
+func Info(format string, v ...interface{}) { newEntry().Info(format, v...) }
+
+// Warn sends a warn log message.
+func Warn(format string, v ...interface{}) { newEntry().Warn(format, v...) }
+
+// Error sends an error log message.
+func Error(format string, v ...interface{}) { newEntry().Error(format, v...) }
+
+// Fatal sends a fatal log message and stop the execution of the program.
+func Fatal(format string, v ...interface{}) { newEntry().Fatal(format, v...) }
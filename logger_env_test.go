@@ -0,0 +1,15 @@
+package logger
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRemoteShipperFromEnvNilByDefault(t *testing.T) {
+	if os.Getenv("LOGGER_REMOTE_SERVER") != "" {
+		t.Skip("LOGGER_REMOTE_SERVER is set in this environment")
+	}
+	if RemoteShipperFromEnv() != nil {
+		t.Fatal("expected no shipper registered when LOGGER_REMOTE_SERVER is unset")
+	}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// HTTPMiddleware attaches a request-scoped Entry carrying a generated
+// request_id, method, path and remote address to the request context, and
+// logs the request's completion with its latency. Handlers further down
+// the chain can pull the same Entry back out with logger.FromContext to
+// add their own fields without re-stating the correlation ID.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := WithFields(Fields{
+			"request_id":  newRequestID(),
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"remote_addr": r.RemoteAddr,
+		})
+
+		start := time.Now()
+		defer func() {
+			entry.WithField("latency_ms", time.Since(start).Milliseconds()).Info("request completed")
+		}()
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), entry)))
+	})
+}
+
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return hex.EncodeToString([]byte(time.Now().String()))[:16]
+	}
+	return hex.EncodeToString(b[:])
+}
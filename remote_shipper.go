@@ -0,0 +1,322 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// RemoteShipper batches Error/Fatal entries and POSTs them to a remote HTTP
+// endpoint. It retries failed batches with exponential backoff and jitter,
+// coalesces repeated identical messages into a single entry with a count,
+// and, when a spill file is set with WithSpillFile, spills to disk instead
+// of blocking or dropping logs when the in-memory queue is full or the
+// endpoint is unreachable; without one that overflow is dropped. It implements
+// Hook, so register it with AddHook like any other sink.
+type RemoteShipper struct {
+	url    string
+	client *http.Client
+
+	batchSize  int
+	flushEvery time.Duration
+	maxQueue   int
+	spillPath  string
+
+	queue    []*shippedMessage
+	index    map[string]*shippedMessage
+	incoming chan string
+	flushReq chan chan struct{}
+	closeReq chan chan struct{}
+}
+
+type shippedMessage struct {
+	Line  string
+	Count int
+}
+
+// ShipperOption configures a RemoteShipper built by NewRemoteShipper.
+type ShipperOption func(*RemoteShipper)
+
+// WithBatchSize flushes as soon as the queue reaches n messages.
+func WithBatchSize(n int) ShipperOption {
+	return func(s *RemoteShipper) { s.batchSize = n }
+}
+
+// WithFlushInterval flushes whatever is queued at least this often.
+func WithFlushInterval(d time.Duration) ShipperOption {
+	return func(s *RemoteShipper) { s.flushEvery = d }
+}
+
+// WithMaxQueue bounds how many distinct messages are buffered in memory
+// before new ones are spilled to disk instead.
+func WithMaxQueue(n int) ShipperOption {
+	return func(s *RemoteShipper) { s.maxQueue = n }
+}
+
+// WithSpillFile sets where messages are written when the queue is full or
+// a batch ultimately fails to send. Without it, overflow is dropped.
+func WithSpillFile(path string) ShipperOption {
+	return func(s *RemoteShipper) { s.spillPath = path }
+}
+
+// NewRemoteShipper starts a RemoteShipper posting batches to url and
+// returns it ready to register via AddHook.
+func NewRemoteShipper(url string, opts ...ShipperOption) *RemoteShipper {
+	s := &RemoteShipper{
+		url:        url,
+		client:     http.DefaultClient,
+		batchSize:  50,
+		flushEvery: 5 * time.Second,
+		maxQueue:   1000,
+		index:      make(map[string]*shippedMessage),
+		incoming:   make(chan string, 256),
+		flushReq:   make(chan chan struct{}),
+		closeReq:   make(chan chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.run()
+	return s
+}
+
+func (s *RemoteShipper) Levels() []LogLevel {
+	return []LogLevel{FatalLevel, ErrorLevel}
+}
+
+func (s *RemoteShipper) Fire(entry *Entry) error {
+	rendered, err := formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	// Formatters always terminate a record with '\n'; strip it since the
+	// shipper stores one record per line/JSON value, not per formatter call.
+	line := strings.TrimRight(string(rendered), "\n")
+
+	select {
+	case s.incoming <- line:
+	default:
+		// The run loop is behind; spill straight to disk rather than
+		// block the caller or silently drop the message.
+		s.spillToDisk(line, 1)
+	}
+	return nil
+}
+
+func (s *RemoteShipper) run() {
+	ticker := time.NewTicker(s.flushEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line := <-s.incoming:
+			s.enqueue(line)
+			if s.batchSize > 0 && len(s.queue) >= s.batchSize {
+				s.flush()
+			}
+		case <-ticker.C:
+			s.flush()
+		case reply := <-s.flushReq:
+			// A message Fire just handed to incoming may not have been
+			// dequeued into s.queue yet; drain it first so Flush's "every
+			// message queued so far" promise actually holds.
+			s.drainIncoming()
+			s.flush()
+			close(reply)
+		case reply := <-s.closeReq:
+			s.drainIncoming()
+			s.flush()
+			s.drainSpill()
+			close(reply)
+			return
+		}
+	}
+}
+
+// drainIncoming moves every message currently buffered on incoming into
+// s.queue without blocking, so a subsequent flush() sees them.
+func (s *RemoteShipper) drainIncoming() {
+	for {
+		select {
+		case line := <-s.incoming:
+			s.enqueue(line)
+		default:
+			return
+		}
+	}
+}
+
+func (s *RemoteShipper) enqueue(line string) {
+	if m, ok := s.index[line]; ok {
+		m.Count++
+		return
+	}
+	if s.maxQueue > 0 && len(s.queue) >= s.maxQueue {
+		s.spillToDisk(line, 1)
+		return
+	}
+	m := &shippedMessage{Line: line, Count: 1}
+	s.queue = append(s.queue, m)
+	s.index[line] = m
+}
+
+func (s *RemoteShipper) flush() {
+	if len(s.queue) == 0 {
+		return
+	}
+	batch := s.queue
+	s.queue = nil
+	s.index = make(map[string]*shippedMessage)
+
+	if err := s.send(batch); err != nil {
+		Warn("remote shipper: giving up on batch of %d messages, spilling to disk: %v", len(batch), err)
+		for _, m := range batch {
+			s.spillToDisk(m.Line, m.Count)
+		}
+	}
+}
+
+// send posts batch, retrying with exponential backoff and jitter on
+// network errors or non-2xx responses.
+func (s *RemoteShipper) send(batch []*shippedMessage) error {
+	body := encodeBatch(batch)
+
+	const maxAttempts = 5
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<uint(attempt-1)) * 200 * time.Millisecond
+			time.Sleep(backoff + time.Duration(rand.Int63n(int64(backoff)+1)))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+		if err != nil {
+			cancel()
+			return fmt.Errorf("remote shipper: build request: %w", err)
+		}
+
+		resp, err := s.client.Do(req)
+		cancel()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("remote shipper: server responded %d", resp.StatusCode)
+	}
+	return lastErr
+}
+
+type shipperRecord struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+func encodeBatch(batch []*shippedMessage) []byte {
+	records := make([]shipperRecord, len(batch))
+	for i, m := range batch {
+		records[i] = shipperRecord{Message: m.Line, Count: m.Count}
+	}
+	data, _ := json.Marshal(records)
+	return data
+}
+
+// spillToDisk appends one JSON-encoded record per line. JSON-encoding
+// escapes any newlines embedded in line (e.g. a stack trace attached to
+// the entry), so each line on disk is always exactly one record.
+func (s *RemoteShipper) spillToDisk(line string, count int) {
+	if s.spillPath == "" {
+		return
+	}
+	record, err := json.Marshal(shipperRecord{Message: line, Count: count})
+	if err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(s.spillPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	record = append(record, '\n')
+	f.Write(record)
+}
+
+// drainSpill makes one best-effort attempt to resend everything in the
+// spill file, removing it on success. It is only called while shutting
+// down, so a failure just leaves the file for the next process to pick up.
+func (s *RemoteShipper) drainSpill() {
+	if s.spillPath == "" {
+		return
+	}
+	data, err := os.ReadFile(s.spillPath)
+	if err != nil || len(data) == 0 {
+		return
+	}
+
+	var batch []*shippedMessage
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var record shipperRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			continue
+		}
+		batch = append(batch, &shippedMessage{Line: record.Message, Count: record.Count})
+	}
+
+	if err := s.send(batch); err == nil {
+		os.Remove(s.spillPath)
+	}
+}
+
+// Flush blocks until every message queued so far has been sent (or
+// spilled to disk after exhausting retries).
+func (s *RemoteShipper) Flush(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case s.flushReq <- reply:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes, makes a best-effort attempt to drain the spill file, and
+// stops the background goroutine. Callers should invoke it during
+// shutdown instead of relying on the old Fatal-only os.Exit(1) path.
+func (s *RemoteShipper) Close(ctx context.Context) error {
+	reply := make(chan struct{})
+	select {
+	case s.closeReq <- reply:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-reply:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestFireThenFlushDrainsIncoming is a regression test for a race where
+// Flush/Close could run before a message Fire had just handed to the
+// incoming channel was dequeued into the run loop's queue, silently
+// dropping it. See drainIncoming.
+func TestFireThenFlushDrainsIncoming(t *testing.T) {
+	var received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for i := 0; i < 200; i++ {
+		atomic.StoreInt32(&received, 0)
+		s := NewRemoteShipper(server.URL)
+
+		if err := s.Fire(&Entry{Level: ErrorLevel, Data: Fields{}, Message: "boom"}); err != nil {
+			t.Fatalf("Fire: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := s.Flush(ctx); err != nil {
+			cancel()
+			t.Fatalf("Flush: %v", err)
+		}
+		cancel()
+
+		if atomic.LoadInt32(&received) != 1 {
+			t.Fatalf("iteration %d: expected the fired message to have been flushed, got %d requests", i, received)
+		}
+	}
+}
+
+func TestSpillAndDrainRoundTrip(t *testing.T) {
+	batches := make(chan []shipperRecord, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var records []shipperRecord
+		json.NewDecoder(r.Body).Decode(&records)
+		batches <- records
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := t.TempDir() + "/spill.jsonl"
+	s := NewRemoteShipper(server.URL, WithSpillFile(path))
+
+	// A message containing embedded newlines (e.g. a stack trace) must
+	// round-trip as a single record, count included.
+	s.spillToDisk("line one\nline two\nline three", 4)
+
+	s.drainSpill()
+
+	got := <-batches
+	if len(got) != 1 || got[0].Message != "line one\nline two\nline three" || got[0].Count != 4 {
+		t.Fatalf("expected one record with the full message and count 4, got %+v", got)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		t.Fatalf("expected spill file to be removed after a successful drain")
+	}
+}
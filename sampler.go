@@ -0,0 +1,21 @@
+package logger
+
+// Sampler decides, for each Entry about to be logged, whether it should
+// actually be emitted. Install one with SetSampler to keep a tight loop
+// calling Error repeatedly from flooding stdout/stderr and every
+// registered hook.
+type Sampler interface {
+	// Allow reports whether entry should be written and handed to hooks.
+	// format is the raw, pre-Sprintf format string, useful for samplers
+	// that key on the call site rather than the interpolated message.
+	Allow(entry *Entry, format string) bool
+}
+
+var sampler Sampler = nil
+
+// SetSampler installs s to filter every Entry before it is written or
+// handed to hooks. Fatal entries are never passed to the sampler and are
+// always emitted. Pass nil to disable sampling (the default).
+func SetSampler(s Sampler) {
+	sampler = s
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+)
+
+var stackTraceDepth = 0
+
+// SetStackTraceDepth sets how many stack frames are captured for Error and
+// Fatal entries. 0 (the default) disables stack capture entirely, matching
+// this package's previous quiet single-line output; opt in with a depth
+// like 32 when you want traces attached.
+func SetStackTraceDepth(depth int) {
+	stackTraceDepth = depth
+}
+
+// captureStack returns up to maxFrames frames from the call stack,
+// starting just outside this package's own frames, formatted as
+// "function\n\tfile:line" entries.
+func captureStack(maxFrames int) []string {
+	if maxFrames <= 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxFrames+10)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []string
+	skippingOwn := true
+	for {
+		frame, more := frames.Next()
+		if skippingOwn {
+			if filepath.Dir(frame.File) == packageDir {
+				if !more {
+					break
+				}
+				continue
+			}
+			skippingOwn = false
+		}
+
+		stack = append(stack, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+		if len(stack) >= maxFrames || !more {
+			break
+		}
+	}
+	return stack
+}
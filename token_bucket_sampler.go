@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucketSampler allows up to Rate events per second per level, with
+// bursts of up to Burst events absorbed instantly.
+type TokenBucketSampler struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[LogLevel]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketSampler returns a sampler allowing eventsPerSecond events
+// per level on average, with bursts of up to burst events.
+func NewTokenBucketSampler(eventsPerSecond float64, burst int) *TokenBucketSampler {
+	return &TokenBucketSampler{
+		rate:    eventsPerSecond,
+		burst:   float64(burst),
+		buckets: make(map[LogLevel]*tokenBucket),
+	}
+}
+
+func (s *TokenBucketSampler) Allow(entry *Entry, format string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[entry.Level]
+	if !ok {
+		b = &tokenBucket{tokens: s.burst, last: time.Now()}
+		s.buckets[entry.Level] = b
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * s.rate
+	if b.tokens > s.burst {
+		b.tokens = s.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}